@@ -0,0 +1,76 @@
+// Copyright 2020 gorse Project Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "time"
+
+// Config is the configuration shared by master and server nodes, pushed from
+// the master to every server node via Master.GetMeta.
+type Config struct {
+	Server   ServerConfig
+	Master   MasterConfig
+	Database DatabaseConfig
+}
+
+// ServerConfig holds settings specific to server nodes.
+type ServerConfig struct {
+	// CacheExpire is how often a server node refreshes its in-process
+	// popular-item and hidden-item caches.
+	CacheExpire time.Duration
+	// Cache configures an optional remote ServerCache backend. Leaving URL
+	// empty keeps the in-process cache.
+	Cache ServerCacheConfig
+	// HiddenBloomFPR is the target false-positive rate for the hidden-items
+	// bloom filter. Defaults to 0.01 when zero.
+	HiddenBloomFPR float64
+}
+
+// ServerCacheConfig configures a remote ServerCache backend.
+type ServerCacheConfig struct {
+	// URL is the backend connection string, e.g. a redis:// URL. Empty
+	// disables the remote cache.
+	URL string
+	// Prefix is prepended to every key the backend reads or writes.
+	Prefix string
+	// TTL is applied to entries written by the backend, where supported.
+	TTL time.Duration
+}
+
+// MasterConfig holds settings for talking to the master node.
+type MasterConfig struct {
+	// MetaTimeout bounds each GetMeta call and, absent backoff, the interval
+	// between them.
+	MetaTimeout time.Duration
+}
+
+// DatabaseConfig holds the data and cache store connection strings.
+type DatabaseConfig struct {
+	DataStore  string
+	CacheStore string
+}
+
+// GetDefaultConfig returns the configuration a node starts with before it
+// has synced with the master.
+func GetDefaultConfig() Config {
+	return Config{
+		Server: ServerConfig{
+			CacheExpire:    time.Minute,
+			HiddenBloomFPR: 0.01,
+		},
+		Master: MasterConfig{
+			MetaTimeout: 10 * time.Second,
+		},
+	}
+}
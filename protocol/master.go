@@ -0,0 +1,136 @@
+// Copyright 2020 gorse Project Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Go bindings for the Master service in protocol.proto, written by hand after
+// protoc-gen-go-grpc conventions rather than generated; regenerate from
+// protocol.proto if protoc tooling is available.
+
+package protocol
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+type NodeType int32
+
+const (
+	NodeType_ServerNode NodeType = 0
+	NodeType_WorkerNode NodeType = 1
+)
+
+type NodeInfo struct {
+	NodeType NodeType
+	NodeName string
+	HttpPort int64
+}
+
+type Meta struct {
+	Config string
+}
+
+// WatchHiddenItemsRequest asks the master to stream hidden-item changes
+// starting right after SinceRevision. Passing 0 asks for the full current
+// set as a backlog of add events, followed by live updates.
+type WatchHiddenItemsRequest struct {
+	SinceRevision int64
+}
+
+// HiddenItemEvent is one increment of the hidden-item set. Revision is
+// monotonically increasing per master; a gap between the last revision a
+// client saw and the next one it receives means it missed updates and must
+// fall back to a full resync.
+type HiddenItemEvent struct {
+	Revision int64
+	Add      []string
+	Remove   []string
+}
+
+// MasterClient is the client API for the Master service.
+type MasterClient interface {
+	GetMeta(ctx context.Context, in *NodeInfo, opts ...grpc.CallOption) (*Meta, error)
+	// WatchHiddenItems streams hidden-item add/remove events starting after
+	// in.SinceRevision.
+	WatchHiddenItems(ctx context.Context, in *WatchHiddenItemsRequest, opts ...grpc.CallOption) (Master_WatchHiddenItemsClient, error)
+}
+
+// NewMasterClient creates a client for the Master service.
+func NewMasterClient(cc grpc.ClientConnInterface) MasterClient {
+	return &masterClient{cc}
+}
+
+type masterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func (c *masterClient) GetMeta(ctx context.Context, in *NodeInfo, opts ...grpc.CallOption) (*Meta, error) {
+	out := new(Meta)
+	if err := c.cc.Invoke(ctx, "/protocol.Master/GetMeta", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var masterWatchHiddenItemsStreamDesc = grpc.StreamDesc{
+	StreamName:    "WatchHiddenItems",
+	ServerStreams: true,
+}
+
+func (c *masterClient) WatchHiddenItems(ctx context.Context, in *WatchHiddenItemsRequest, opts ...grpc.CallOption) (Master_WatchHiddenItemsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &masterWatchHiddenItemsStreamDesc, "/protocol.Master/WatchHiddenItems", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &masterWatchHiddenItemsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// Master_WatchHiddenItemsClient is the client-side stream returned by
+// MasterClient.WatchHiddenItems.
+type Master_WatchHiddenItemsClient interface {
+	Recv() (*HiddenItemEvent, error)
+	grpc.ClientStream
+}
+
+type masterWatchHiddenItemsClient struct {
+	grpc.ClientStream
+}
+
+func (x *masterWatchHiddenItemsClient) Recv() (*HiddenItemEvent, error) {
+	m := new(HiddenItemEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MasterServer is the server API for the Master service.
+type MasterServer interface {
+	GetMeta(context.Context, *NodeInfo) (*Meta, error)
+	WatchHiddenItems(*WatchHiddenItemsRequest, Master_WatchHiddenItemsServer) error
+}
+
+// Master_WatchHiddenItemsServer is the server-side stream passed to
+// MasterServer.WatchHiddenItems.
+type Master_WatchHiddenItemsServer interface {
+	Send(*HiddenItemEvent) error
+	grpc.ServerStream
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 gorse Project Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/juju/errors"
+	"github.com/zhenghaoz/gorse/config"
+	"github.com/zhenghaoz/gorse/storage/cache"
+)
+
+// ServerCache is implemented by remote backends that can serve popularity scores
+// and hidden-item membership directly, so a server node doesn't have to keep its
+// own full copy in memory. PopularItemsCache and HiddenItemsCache fall back to
+// their in-process behavior whenever no ServerCache is configured.
+type ServerCache interface {
+	// GetPopularScore returns the popularity score of an item.
+	GetPopularScore(itemId string) (float64, error)
+	// IsHidden reports, for each of members, whether the item is hidden.
+	IsHidden(members []string) ([]bool, error)
+	// Close releases the resources (e.g. connections) held by the backend.
+	Close() error
+}
+
+// NewServerCache creates a ServerCache backend from the server configuration.
+// It returns nil, nil when no remote cache is configured, in which case callers
+// should keep using their in-process cache.
+func NewServerCache(cfg config.ServerCacheConfig) (ServerCache, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	return newRedisServerCache(cfg)
+}
+
+// redisServerCache is a ServerCache backed by Redis. Popular scores are read
+// from the `cache.PopularItems` sorted set with ZSCORE, and hidden-item checks
+// are served from the `cache.HiddenItemsV2` sorted set (members are scored by
+// the timestamp at which they were marked hidden) via a pipelined ZSCORE batch.
+// Both hit Redis directly on every call, so there's no local state to
+// invalidate and nothing to subscribe to.
+type redisServerCache struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+func newRedisServerCache(cfg config.ServerCacheConfig) (*redisServerCache, error) {
+	opt, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c := &redisServerCache{
+		client: redis.NewClient(opt),
+		prefix: cfg.Prefix,
+		ttl:    cfg.TTL,
+	}
+	if err = c.client.Ping(context.Background()).Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c, nil
+}
+
+func (c *redisServerCache) key(suffix string) string {
+	return c.prefix + suffix
+}
+
+func (c *redisServerCache) GetPopularScore(itemId string) (float64, error) {
+	score, err := c.client.ZScore(context.Background(), c.key(cache.Key(cache.PopularItems)), itemId).Result()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return score, nil
+}
+
+func (c *redisServerCache) IsHidden(members []string) ([]bool, error) {
+	ctx := context.Background()
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.FloatCmd, len(members))
+	for i, member := range members {
+		cmds[i] = pipe.ZScore(ctx, c.key(cache.HiddenItemsV2), member)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, errors.Trace(err)
+	}
+	now := float64(time.Now().Unix())
+	hidden := make([]bool, len(members))
+	for i, cmd := range cmds {
+		score, err := cmd.Result()
+		if err != nil {
+			if err != redis.Nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
+		// score is the Unix timestamp at which the item was marked hidden (see
+		// HiddenItemsCache.sync), so it's hidden as soon as that time arrives.
+		hidden[i] = score <= now
+	}
+	return hidden, nil
+}
+
+func (c *redisServerCache) Close() error {
+	return errors.Trace(c.client.Close())
+}
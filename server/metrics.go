@@ -0,0 +1,49 @@
+// Copyright 2023 gorse Project Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	SyncLastSuccessTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gorse",
+		Subsystem: "server",
+		Name:      "sync_last_success_timestamp_seconds",
+	})
+	SyncConsecutiveFailures = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gorse",
+		Subsystem: "server",
+		Name:      "sync_consecutive_failures",
+	})
+	SyncCurrentBackoffSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gorse",
+		Subsystem: "server",
+		Name:      "sync_current_backoff_seconds",
+	})
+
+	HiddenBloomFPR = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gorse",
+		Subsystem: "server",
+		Name:      "hidden_bloom_fpr",
+	})
+	HiddenBloomSizeBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gorse",
+		Subsystem: "server",
+		Name:      "hidden_bloom_size_bytes",
+	})
+)
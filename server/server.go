@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/juju/errors"
 	"github.com/samber/lo"
@@ -38,14 +39,58 @@ import (
 // Server manages states of a server node.
 type Server struct {
 	RestServer
-	cachePath    string
-	dataPath     string
-	masterClient protocol.MasterClient
-	serverName   string
-	masterHost   string
-	masterPort   int
-	testMode     bool
-	cacheFile    string
+	cachePath       string
+	dataPath        string
+	masterClient    protocol.MasterClient
+	serverName      string
+	masterHost      string
+	masterPort      int
+	testMode        bool
+	cacheFile       string
+	syncCancel      context.CancelFunc
+	syncHealth      syncHealth
+	cacheBackendURL string
+}
+
+const (
+	minSyncBackoff = time.Second
+	maxSyncBackoff = 30 * time.Second
+)
+
+// syncHealth tracks the state of the meta sync loop for the /api/health/sync
+// endpoint and the gorse_server_sync_* Prometheus gauges.
+type syncHealth struct {
+	mu                  sync.RWMutex
+	lastSuccess         time.Time
+	consecutiveFailures int
+	currentBackoff      time.Duration
+}
+
+func (h *syncHealth) recordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastSuccess = time.Now()
+	h.consecutiveFailures = 0
+	h.currentBackoff = 0
+	SyncLastSuccessTimestamp.Set(float64(h.lastSuccess.Unix()))
+	SyncConsecutiveFailures.Set(0)
+	SyncCurrentBackoffSeconds.Set(0)
+}
+
+func (h *syncHealth) recordFailure(backoff time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveFailures++
+	h.currentBackoff = backoff
+	SyncConsecutiveFailures.Set(float64(h.consecutiveFailures))
+	SyncCurrentBackoffSeconds.Set(backoff.Seconds())
+}
+
+// SyncHealth is the JSON body returned by /api/health/sync.
+type SyncHealth struct {
+	LastSuccess         time.Time `json:"last_success"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CurrentBackoff      string    `json:"current_backoff"`
 }
 
 // NewServer creates a server node.
@@ -63,8 +108,13 @@ func NewServer(masterHost string, masterPort int, serverHost string, serverPort
 			WebService:  new(restful.WebService),
 		},
 	}
-	s.RestServer.PopularItemsCache = NewPopularItemsCache(&s.RestServer)
-	s.RestServer.HiddenItemsCache = NewHiddenItemsCache(&s.RestServer)
+	remoteCache, err := NewServerCache(s.RestServer.GorseConfig.Server.Cache)
+	if err != nil {
+		base.Logger().Error("failed to connect server cache, falling back to in-process cache", zap.Error(err))
+		remoteCache = nil
+	}
+	s.RestServer.PopularItemsCache = NewPopularItemsCache(&s.RestServer, remoteCache)
+	s.RestServer.HiddenItemsCache = NewHiddenItemsCache(&s.RestServer, remoteCache)
 	return s
 }
 
@@ -98,60 +148,134 @@ func (s *Server) Serve() {
 		base.Logger().Fatal("failed to connect master", zap.Error(err))
 	}
 	s.masterClient = protocol.NewMasterClient(conn)
+	s.RestServer.HiddenItemsCache.StartWatch(s.masterClient)
+	s.WebService.Route(s.WebService.GET("/api/health/sync").To(s.getSyncHealth))
 
-	go s.Sync()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.syncCancel = cancel
+	go s.Sync(ctx)
 	s.StartHttpServer()
 }
 
-// Sync this server to the master.
-func (s *Server) Sync() {
+// Close stops the meta sync loop started by Serve. The hidden-items watch loop
+// and the cache refresh loops keep running.
+func (s *Server) Close() {
+	if s.syncCancel != nil {
+		s.syncCancel()
+	}
+}
+
+// Sync this server to the master, reconnecting data/cache stores whenever the
+// master's config changes. ctx cancellation (via Server.Close) stops the loop
+// cleanly; consecutive GetMeta failures back off exponentially with jitter,
+// capped at maxSyncBackoff, instead of sleeping a fixed MetaTimeout.
+func (s *Server) Sync(ctx context.Context) {
 	defer base.CheckPanic()
 	base.Logger().Info("start meta sync", zap.Duration("meta_timeout", s.GorseConfig.Master.MetaTimeout))
+	backoff := minSyncBackoff
 	for {
-		var meta *protocol.Meta
-		var err error
-		if meta, err = s.masterClient.GetMeta(context.Background(),
-			&protocol.NodeInfo{
-				NodeType: protocol.NodeType_ServerNode,
-				NodeName: s.serverName,
-				HttpPort: int64(s.HttpPort),
-			}); err != nil {
-			base.Logger().Error("failed to get meta", zap.Error(err))
-			goto sleep
+		var wait time.Duration
+		if err := s.syncOnce(ctx); err != nil {
+			base.Logger().Error("failed to sync meta", zap.Error(err))
+			s.syncHealth.recordFailure(backoff)
+			wait = withJitter(backoff)
+			if backoff *= 2; backoff > maxSyncBackoff {
+				backoff = maxSyncBackoff
+			}
+		} else {
+			s.syncHealth.recordSuccess()
+			backoff = minSyncBackoff
+			wait = s.GorseConfig.Master.MetaTimeout
 		}
 
-		// load master config
-		err = json.Unmarshal([]byte(meta.Config), &s.GorseConfig)
-		if err != nil {
-			base.Logger().Error("failed to parse master config", zap.Error(err))
-			goto sleep
+		if s.testMode {
+			return
 		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
 
-		// connect to data store
-		if s.dataPath != s.GorseConfig.Database.DataStore {
-			base.Logger().Info("connect data store", zap.String("database", s.GorseConfig.Database.DataStore))
-			if s.DataClient, err = data.Open(s.GorseConfig.Database.DataStore); err != nil {
-				base.Logger().Error("failed to connect data store", zap.Error(err))
-				goto sleep
-			}
-			s.dataPath = s.GorseConfig.Database.DataStore
+// syncOnce fetches meta from the master, applies it, and reconnects the data
+// and cache stores if they changed. The GetMeta call is bounded by
+// GorseConfig.Master.MetaTimeout; opening the stores and the server cache
+// backend is not.
+func (s *Server) syncOnce(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, s.GorseConfig.Master.MetaTimeout)
+	defer cancel()
+	meta, err := s.masterClient.GetMeta(timeoutCtx, &protocol.NodeInfo{
+		NodeType: protocol.NodeType_ServerNode,
+		NodeName: s.serverName,
+		HttpPort: int64(s.HttpPort),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// load master config
+	if err = json.Unmarshal([]byte(meta.Config), &s.GorseConfig); err != nil {
+		return errors.Trace(err)
+	}
+
+	// connect to data store
+	if s.dataPath != s.GorseConfig.Database.DataStore {
+		base.Logger().Info("connect data store", zap.String("database", s.GorseConfig.Database.DataStore))
+		if s.DataClient, err = data.Open(s.GorseConfig.Database.DataStore); err != nil {
+			return errors.Trace(err)
 		}
+		s.dataPath = s.GorseConfig.Database.DataStore
+	}
 
-		// connect to cache store
-		if s.cachePath != s.GorseConfig.Database.CacheStore {
-			base.Logger().Info("connect cache store", zap.String("database", s.GorseConfig.Database.CacheStore))
-			if s.CacheClient, err = cache.Open(s.GorseConfig.Database.CacheStore); err != nil {
-				base.Logger().Error("failed to connect cache store", zap.Error(err))
-				goto sleep
-			}
-			s.cachePath = s.GorseConfig.Database.CacheStore
+	// connect to cache store
+	if s.cachePath != s.GorseConfig.Database.CacheStore {
+		base.Logger().Info("connect cache store", zap.String("database", s.GorseConfig.Database.CacheStore))
+		if s.CacheClient, err = cache.Open(s.GorseConfig.Database.CacheStore); err != nil {
+			return errors.Trace(err)
 		}
+		s.cachePath = s.GorseConfig.Database.CacheStore
+	}
 
-	sleep:
-		if s.testMode {
-			return
+	// (re)connect the remote server cache backend. This only runs once the
+	// master's real config has been loaded above, since GorseConfig starts out
+	// as config.GetDefaultConfig() (Server.Cache.URL always empty) until then.
+	if s.GorseConfig.Server.Cache.URL != s.cacheBackendURL {
+		base.Logger().Info("server cache backend changed", zap.String("url", s.GorseConfig.Server.Cache.URL))
+		remoteCache, err := NewServerCache(s.GorseConfig.Server.Cache)
+		if err != nil {
+			base.Logger().Error("failed to connect server cache, falling back to in-process cache", zap.Error(err))
+			remoteCache = nil
 		}
-		time.Sleep(s.GorseConfig.Master.MetaTimeout)
+		s.PopularItemsCache.SetCache(remoteCache)
+		s.HiddenItemsCache.SetCache(remoteCache)
+		s.cacheBackendURL = s.GorseConfig.Server.Cache.URL
+	}
+	return nil
+}
+
+// withJitter returns d scaled by a random factor in [0.5, 1.5), so that many
+// server nodes backing off at once don't retry in lockstep.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+// getSyncHealth reports the current state of the meta sync loop.
+func (s *Server) getSyncHealth(request *restful.Request, response *restful.Response) {
+	s.syncHealth.mu.RLock()
+	defer s.syncHealth.mu.RUnlock()
+	if err := response.WriteAsJson(SyncHealth{
+		LastSuccess:         s.syncHealth.lastSuccess,
+		ConsecutiveFailures: s.syncHealth.consecutiveFailures,
+		CurrentBackoff:      s.syncHealth.currentBackoff.String(),
+	}); err != nil {
+		base.Logger().Error("failed to write sync health", zap.Error(err))
 	}
 }
 
@@ -159,21 +283,28 @@ type PopularItemsCache struct {
 	mu     sync.RWMutex
 	scores map[string]float64
 	server *RestServer
+	cache  ServerCache
 	test   bool
 }
 
-func NewPopularItemsCache(s *RestServer) *PopularItemsCache {
+// NewPopularItemsCache creates a server-side cache of popular-item scores. If cache
+// is non-nil, scores are served from it directly; otherwise the cache keeps its own
+// in-process copy, refreshed every GorseConfig.Server.CacheExpire.
+func NewPopularItemsCache(s *RestServer, cache ServerCache) *PopularItemsCache {
 	sc := &PopularItemsCache{
 		server: s,
+		cache:  cache,
 		scores: make(map[string]float64),
 	}
-	go func() {
-		for {
-			sc.sync()
-			base.Logger().Debug("refresh server side popular items cache", zap.String("cache_expire", s.GorseConfig.Server.CacheExpire.String()))
-			time.Sleep(s.GorseConfig.Server.CacheExpire)
-		}
-	}()
+	if sc.cache == nil {
+		go func() {
+			for {
+				sc.sync()
+				base.Logger().Debug("refresh server side popular items cache", zap.String("cache_expire", s.GorseConfig.Server.CacheExpire.String()))
+				time.Sleep(s.GorseConfig.Server.CacheExpire)
+			}
+		}()
+	}
 	return sc
 }
 
@@ -204,7 +335,26 @@ func (sc *PopularItemsCache) sync() {
 	sc.scores = scores
 }
 
+// SetCache swaps in a new remote ServerCache backend, or nil to fall back to
+// the in-process cache, e.g. when Server.Cache changes after a sync.
+func (sc *PopularItemsCache) SetCache(cache ServerCache) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.cache = cache
+}
+
 func (sc *PopularItemsCache) GetSortedScore(member string) float64 {
+	sc.mu.RLock()
+	remoteCache := sc.cache
+	sc.mu.RUnlock()
+	if remoteCache != nil {
+		score, err := remoteCache.GetPopularScore(member)
+		if err != nil {
+			base.Logger().Error("failed to get popular score from server cache", zap.Error(err))
+			return 0
+		}
+		return score
+	}
 	if sc.test {
 		sc.sync()
 	}
@@ -214,29 +364,130 @@ func (sc *PopularItemsCache) GetSortedScore(member string) float64 {
 	return score
 }
 
+// defaultHiddenBloomFPR is used when GorseConfig.Server.HiddenBloomFPR is unset.
+const defaultHiddenBloomFPR = 0.01
+
 type HiddenItemsCache struct {
-	server      *RestServer
-	mu          sync.RWMutex
-	hiddenItems *strset.Set
-	updateTime  time.Time
-	test        bool
+	server       *RestServer
+	mu           sync.RWMutex
+	hiddenItems  *strset.Set
+	bloom        *bloom.BloomFilter
+	updateTime   time.Time
+	cache        ServerCache
+	masterClient protocol.MasterClient
+	revision     int64
+	watching     bool
+	test         bool
 }
 
-func NewHiddenItemsCache(s *RestServer) *HiddenItemsCache {
+// NewHiddenItemsCache creates a server-side cache of hidden items. If cache is
+// non-nil, membership checks are served from it directly; otherwise the cache
+// keeps its own in-process copy, kept up to date by StartWatch and falling
+// back to a full sync every GorseConfig.Server.CacheExpire whenever the watch
+// stream isn't connected.
+func NewHiddenItemsCache(s *RestServer, cache ServerCache) *HiddenItemsCache {
 	hc := &HiddenItemsCache{
 		server:      s,
+		cache:       cache,
 		hiddenItems: strset.New(),
 	}
-	go func() {
-		for {
-			hc.sync()
-			base.Logger().Debug("refresh server side hidden items cache", zap.String("cache_expire", s.GorseConfig.Server.CacheExpire.String()))
-			time.Sleep(hc.server.GorseConfig.Server.CacheExpire)
-		}
-	}()
+	if hc.cache == nil {
+		go func() {
+			for {
+				hc.mu.RLock()
+				watching := hc.watching
+				hc.mu.RUnlock()
+				if !watching {
+					hc.sync()
+				}
+				base.Logger().Debug("refresh server side hidden items cache", zap.String("cache_expire", s.GorseConfig.Server.CacheExpire.String()))
+				time.Sleep(hc.server.GorseConfig.Server.CacheExpire)
+			}
+		}()
+	}
 	return hc
 }
 
+// StartWatch subscribes to the master's WatchHiddenItems stream so hidden-item
+// changes are applied to hiddenItems incrementally instead of waiting for the
+// next periodic sync. It is a no-op when a remote ServerCache is configured,
+// since that backend maintains its own freshness.
+func (hc *HiddenItemsCache) StartWatch(client protocol.MasterClient) {
+	hc.mu.RLock()
+	remoteCache := hc.cache
+	hc.mu.RUnlock()
+	if remoteCache != nil {
+		return
+	}
+	hc.masterClient = client
+	go hc.watch()
+}
+
+// SetCache swaps in a new remote ServerCache backend, or nil to fall back to
+// the in-process cache, e.g. when Server.Cache changes after a sync.
+func (hc *HiddenItemsCache) SetCache(cache ServerCache) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.cache = cache
+}
+
+// watch runs the WatchHiddenItems stream, reconnecting and falling back to a
+// full sync whenever the stream errors out or reports a gap in revisions.
+func (hc *HiddenItemsCache) watch() {
+	defer base.CheckPanic()
+	for {
+		if err := hc.watchOnce(); err != nil {
+			base.Logger().Error("hidden items watch stream failed, falling back to periodic sync", zap.Error(err))
+		}
+		hc.mu.Lock()
+		hc.watching = false
+		hc.mu.Unlock()
+		time.Sleep(time.Second)
+	}
+}
+
+func (hc *HiddenItemsCache) watchOnce() error {
+	hc.mu.RLock()
+	revision := hc.revision
+	hc.mu.RUnlock()
+	stream, err := hc.masterClient.WatchHiddenItems(context.Background(),
+		&protocol.WatchHiddenItemsRequest{SinceRevision: revision})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// seed with a full sync before trusting incremental updates
+	hc.sync()
+	hc.mu.Lock()
+	hc.watching = true
+	hc.mu.Unlock()
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		hc.mu.Lock()
+		if hc.revision != 0 && event.Revision != hc.revision+1 {
+			hc.mu.Unlock()
+			return errors.Errorf("revision gap detected: have %v, got %v", hc.revision, event.Revision)
+		}
+		for _, item := range event.Add {
+			hc.hiddenItems.Add(item)
+			if hc.bloom != nil {
+				hc.bloom.AddString(item)
+			}
+		}
+		for _, item := range event.Remove {
+			// bloom filters can't forget a member; a stray positive here just
+			// means IsHidden falls through to the exact check, until the next
+			// full sync rebuilds the filter without it.
+			hc.hiddenItems.Remove(item)
+		}
+		hc.revision = event.Revision
+		hc.updateTime = time.Now()
+		hc.mu.Unlock()
+	}
+}
+
 func (hc *HiddenItemsCache) sync() {
 	ts := time.Now()
 	// load hidden items
@@ -247,28 +498,93 @@ func (hc *HiddenItemsCache) sync() {
 		}
 		return
 	}
-	if len(score) > 0 {
-		fmt.Println(score)
-	}
-	hiddenItems := strset.New(cache.RemoveScores(score)...)
+	items := cache.RemoveScores(score)
+	hiddenItems := strset.New(items...)
+	filter := newHiddenBloomFilter(items, hc.hiddenBloomFPR())
 	hc.mu.Lock()
 	defer hc.mu.Unlock()
 	hc.hiddenItems = hiddenItems
+	hc.bloom = filter
 	hc.updateTime = ts
 }
 
+// hiddenBloomFPR returns the configured target false-positive rate for the
+// bloom filter, falling back to defaultHiddenBloomFPR when unset.
+func (hc *HiddenItemsCache) hiddenBloomFPR() float64 {
+	if hc.server == nil || hc.server.GorseConfig.Server.HiddenBloomFPR <= 0 {
+		return defaultHiddenBloomFPR
+	}
+	return hc.server.GorseConfig.Server.HiddenBloomFPR
+}
+
+// newHiddenBloomFilter builds a bloom filter sized for len(items) at the given
+// target false-positive rate. It is rebuilt from scratch on every full sync,
+// so it never needs to support removal.
+func newHiddenBloomFilter(items []string, fpr float64) *bloom.BloomFilter {
+	n := uint(len(items))
+	if n == 0 {
+		n = 1
+	}
+	filter := bloom.NewWithEstimates(n, fpr)
+	for _, item := range items {
+		filter.AddString(item)
+	}
+	HiddenBloomFPR.Set(fpr)
+	HiddenBloomSizeBytes.Set(float64(filter.Cap() / 8))
+	return filter
+}
+
 func (hc *HiddenItemsCache) IsHidden(members []string) ([]bool, error) {
 	hc.mu.RLock()
+	remoteCache := hc.cache
+	hc.mu.RUnlock()
+	if remoteCache != nil {
+		return remoteCache.IsHidden(members)
+	}
+	hc.mu.RLock()
+	if hc.watching {
+		// hiddenItems and bloom are kept exactly up to date by the watch stream,
+		// so a negative bloom test here can only mean "not hidden" and can be
+		// answered without touching the exact set at all. Hold the read lock for
+		// both: watchOnce mutates the same hiddenItems/bloom objects in place
+		// under the write lock, rather than swapping in new ones.
+		defer hc.mu.RUnlock()
+		result := make([]bool, len(members))
+		var candidates []string
+		var candidateIdx []int
+		if hc.bloom != nil {
+			for i, member := range members {
+				if hc.bloom.TestString(member) {
+					candidates = append(candidates, member)
+					candidateIdx = append(candidateIdx, i)
+				}
+			}
+		} else {
+			candidates = members
+			candidateIdx = make([]int, len(members))
+			for i := range candidateIdx {
+				candidateIdx[i] = i
+			}
+		}
+		for i, idx := range candidateIdx {
+			result[idx] = hc.hiddenItems.Has(candidates[i])
+		}
+		return result, nil
+	}
 	hiddenItems := hc.hiddenItems
 	updateTime := hc.updateTime
 	hc.mu.RUnlock()
-	// load hidden items
+
+	// Polling path: the bloom filter is only rebuilt on a full sync, so it
+	// doesn't cover the delta window since updateTime that this path's
+	// cache-store round-trip exists to cover. Check every member against the
+	// exact set and the delta instead of gating on the (possibly stale) filter.
 	score, err := hc.server.CacheClient.GetSortedByScore(cache.HiddenItemsV2, float64(updateTime.Unix()), float64(time.Now().Unix()))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	deltaHiddenItems := strset.New(cache.RemoveScores(score)...)
-	return lo.Map(members, func(t string, i int) bool {
+	return lo.Map(members, func(t string, _ int) bool {
 		return hiddenItems.Has(t) || deltaHiddenItems.Has(t)
 	}), nil
 }
@@ -0,0 +1,89 @@
+// Copyright 2023 gorse Project Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/scylladb/go-set/strset"
+	"github.com/zhenghaoz/gorse/storage/cache"
+)
+
+// benchHiddenItems returns n hidden item ids and m query ids disjoint from
+// them, exercising the miss path that the bloom filter fast path targets.
+func benchHiddenItems(n, m int) (hidden, queries []string) {
+	hidden = make([]string, n)
+	for i := 0; i < n; i++ {
+		hidden[i] = fmt.Sprintf("hidden-%d", i)
+	}
+	queries = make([]string, m)
+	for i := 0; i < m; i++ {
+		queries[i] = fmt.Sprintf("query-%d", i)
+	}
+	return
+}
+
+func BenchmarkHiddenItemsCache_IsHidden(b *testing.B) {
+	for _, n := range []int{10_000, 1_000_000} {
+		hidden, queries := benchHiddenItems(n, 1000)
+		hiddenItems := strset.New(hidden...)
+
+		b.Run(fmt.Sprintf("exact/%d", n), func(b *testing.B) {
+			hc := &HiddenItemsCache{hiddenItems: hiddenItems, watching: true}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := hc.IsHidden(queries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("bloom/%d", n), func(b *testing.B) {
+			hc := &HiddenItemsCache{
+				hiddenItems: hiddenItems,
+				bloom:       newHiddenBloomFilter(hidden, defaultHiddenBloomFPR),
+				watching:    true,
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := hc.IsHidden(queries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		// The polling path (watching: false) checks every query against the
+		// delta regardless of the bloom filter, since the filter can go stale
+		// between full syncs. This measures the cost of that path; it does not
+		// check the results IsHidden returns.
+		b.Run(fmt.Sprintf("polling/%d", n), func(b *testing.B) {
+			hc := &HiddenItemsCache{
+				server:      &RestServer{CacheClient: &cache.NoDatabase{}},
+				hiddenItems: hiddenItems,
+				bloom:       newHiddenBloomFilter(hidden, defaultHiddenBloomFPR),
+				updateTime:  time.Now(),
+				watching:    false,
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := hc.IsHidden(queries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
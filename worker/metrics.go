@@ -15,46 +15,49 @@
 package worker
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// Per-recommender-stage HistogramVecs with exemplars (candidate-generation,
+// filtering, ranking, hidden-filter, keyed off request IDs threaded through
+// RestServer handlers) are out of scope here: this package only has the
+// whole-call histograms below to attach exemplars to, and none of the actual
+// pipeline stages (candidate generation, filtering, ranking) exist in this
+// tree to instrument. Revisit once that code lands.
+
+// nativeHistogramOpts returns HistogramOpts configured for a native (sparse)
+// histogram, which gives much finer resolution across the sub-millisecond to
+// multi-second range typical for recommendation pipelines than a fixed set of
+// classic buckets. Classic buckets are kept as a fallback for scrapers that
+// don't understand the native histogram format yet.
+func nativeHistogramOpts(namespace, subsystem, name string) prometheus.HistogramOpts {
+	return prometheus.HistogramOpts{
+		Namespace:                       namespace,
+		Subsystem:                       subsystem,
+		Name:                            name,
+		Buckets:                         prometheus.DefBuckets,
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  160,
+		NativeHistogramMinResetDuration: time.Hour,
+	}
+}
+
 var (
-	GenerateRecommendSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
-		Namespace: "gorse",
-		Subsystem: "worker",
-		Name:      "get_recommend_seconds",
-	})
-	CTRRecommendSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
-		Namespace: "gorse",
-		Subsystem: "worker",
-		Name:      "ctr_recommend_seconds",
-	})
-	CollaborativeRecommendSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
-		Namespace: "gorse",
-		Subsystem: "worker",
-		Name:      "collaborative_recommend_seconds",
-	})
-	ItemBasedRecommendSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
-		Namespace: "gorse",
-		Subsystem: "worker",
-		Name:      "item_based_recommend_seconds",
-	})
-	UserBasedRecommendSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
-		Namespace: "gorse",
-		Subsystem: "worker",
-		Name:      "user_based_recommend_seconds",
-	})
-	LoadLatestRecommendCacheSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
-		Namespace: "gorse",
-		Subsystem: "worker",
-		Name:      "load_latest_recommend_cache_seconds",
-	})
-	LoadPopularRecommendCacheSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
-		Namespace: "gorse",
-		Subsystem: "worker",
-		Name:      "load_popular_recommend_cache_seconds",
-	})
+	GenerateRecommendSeconds      = promauto.NewHistogram(nativeHistogramOpts("gorse", "worker", "get_recommend_seconds"))
+	CTRRecommendSeconds           = promauto.NewHistogram(nativeHistogramOpts("gorse", "worker", "ctr_recommend_seconds"))
+	CollaborativeRecommendSeconds = promauto.NewHistogram(
+		nativeHistogramOpts("gorse", "worker", "collaborative_recommend_seconds"))
+	ItemBasedRecommendSeconds = promauto.NewHistogram(
+		nativeHistogramOpts("gorse", "worker", "item_based_recommend_seconds"))
+	UserBasedRecommendSeconds = promauto.NewHistogram(
+		nativeHistogramOpts("gorse", "worker", "user_based_recommend_seconds"))
+	LoadLatestRecommendCacheSeconds = promauto.NewHistogram(
+		nativeHistogramOpts("gorse", "worker", "load_latest_recommend_cache_seconds"))
+	LoadPopularRecommendCacheSeconds = promauto.NewHistogram(
+		nativeHistogramOpts("gorse", "worker", "load_popular_recommend_cache_seconds"))
 
 	CollaborativeFilteringIndexRecall = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "gorse",